@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims holds the fields from the admin JWT that handlers care about.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+type contextKey int
+
+// claimsContextKey is the typed key used to stash parsed JWT claims on the
+// request context; unexported so callers must go through WithClaims and
+// ClaimsFromContext.
+const claimsContextKey contextKey = iota
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey, claims)
+}
+
+// ClaimsFromContext returns the Claims stashed by WithClaims, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*Claims)
+	return claims, ok
+}
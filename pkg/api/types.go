@@ -0,0 +1,31 @@
+// Package api holds the request/response contracts shared by the server's
+// handlers and middleware.
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// User represents a user in our system
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Response represents a standard API response
+type Response struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// WriteJSON encodes v as JSON with the given status code.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package handlers implements the demo server's HTTP endpoints.
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/digitalis-io/opentelemtry-local/pkg/api"
+	"github.com/digitalis-io/opentelemtry-local/pkg/simulate"
+)
+
+// Handlers holds the state the demo endpoints need to serve requests. The
+// simulators are injected as interfaces so tests can swap in deterministic
+// fakes instead of the config-driven default.
+type Handlers struct {
+	StartTime   time.Time
+	DB          simulate.DBSimulator
+	ExternalAPI simulate.ExternalAPISimulator
+}
+
+// New creates a Handlers set with the given server start time and
+// simulators.
+func New(startTime time.Time, db simulate.DBSimulator, externalAPI simulate.ExternalAPISimulator) *Handlers {
+	return &Handlers{StartTime: startTime, DB: db, ExternalAPI: externalAPI}
+}
+
+// Good handles the /good endpoint.
+func (h *Handlers) Good(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log.Printf("Processing good request from %s", r.RemoteAddr)
+
+	// Simulate some business logic with database calls
+	if err := h.DB.Query(ctx, "SELECT users WHERE active=true"); err != nil {
+		log.Printf("Database error: %v", err)
+		http.Error(w, "Database unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Simulate calling an external service
+	externalData, err := h.ExternalAPI.Call(ctx, "https://api.example.com/status")
+	if err != nil {
+		log.Printf("External API error: %v", err)
+		// Continue anyway for demo purposes
+	}
+
+	// Create some users data
+	users := []api.User{
+		{ID: 1, Name: "Alice Johnson"},
+		{ID: 2, Name: "Bob Smith"},
+		{ID: 3, Name: "Charlie Brown"},
+	}
+
+	api.WriteJSON(w, http.StatusOK, api.Response{
+		Status:  "success",
+		Message: "Request processed successfully",
+		Data: map[string]interface{}{
+			"users":         users,
+			"external_data": externalData,
+			"processed_at":  time.Now().Format(time.RFC3339),
+		},
+	})
+
+	log.Printf("Successfully processed good request")
+}
+
+// Bad handles the /bad endpoint.
+func (h *Handlers) Bad(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span := trace.SpanFromContext(ctx)
+	log.Printf("Processing bad request from %s", r.RemoteAddr)
+
+	// Simulate some processing that leads to an error
+	if err := h.DB.Query(ctx, "SELECT * FROM non_existent_table"); err != nil {
+		log.Printf("Expected database error: %v", err)
+	}
+
+	// Simulate multiple failed operations
+	operations := []string{
+		"validate_user_permissions",
+		"check_rate_limits",
+		"process_payment",
+	}
+
+	for _, op := range operations {
+		log.Printf("Operation failed: %s", op)
+		// Add some artificial delay to make traces more interesting
+		time.Sleep(time.Duration(rand.Intn(20)+5) * time.Millisecond)
+	}
+
+	// Try external API call that will "fail"
+	_, err := h.ExternalAPI.Call(ctx, "https://api.example.com/broken-endpoint")
+	if err != nil {
+		log.Printf("External API call failed as expected: %v", err)
+	}
+
+	internalErr := fmt.Errorf("internal server error occurred")
+	span.RecordError(internalErr)
+	span.SetStatus(codes.Error, internalErr.Error())
+
+	api.WriteJSON(w, http.StatusInternalServerError, api.Response{
+		Status:  "error",
+		Message: "Internal server error occurred",
+		Data: map[string]interface{}{
+			"error_code": "INTERNAL_ERROR",
+			"timestamp":  time.Now().Format(time.RFC3339),
+		},
+	})
+
+	log.Printf("Processed bad request with error response")
+}
+
+// Admin handles the /admin endpoint. By the time it runs, the server's
+// auth middleware has already verified the caller's JWT and rejected
+// anyone without the admin role, so it only has to serve the privileged
+// data. It still fails closed if claims are somehow missing, rather than
+// trusting that wiring elsewhere guarantees it ran after RequireRole.
+func (h *Handlers) Admin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, ok := api.ClaimsFromContext(ctx)
+	if !ok {
+		log.Printf("Admin handler reached without authenticated claims")
+		api.WriteJSON(w, http.StatusInternalServerError, api.Response{
+			Status:  "error",
+			Message: "Authentication context missing",
+			Data:    map[string]interface{}{"error_code": "MISSING_CLAIMS"},
+		})
+		return
+	}
+	log.Printf("Admin access granted to %s", claims.Subject)
+
+	// Simulate database call to check permissions
+	if err := h.DB.Query(ctx, "SELECT permissions FROM users WHERE token=?"); err != nil {
+		log.Printf("Auth database error: %v", err)
+	}
+
+	api.WriteJSON(w, http.StatusOK, api.Response{
+		Status:  "success",
+		Message: "Admin access granted",
+		Data: map[string]interface{}{
+			"subject":   claims.Subject,
+			"role":      claims.Role,
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
+	})
+
+	log.Printf("Processed admin request for %s", claims.Subject)
+}
+
+// Health provides a simple health check.
+func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Health check from %s", r.RemoteAddr)
+
+	api.WriteJSON(w, http.StatusOK, api.Response{
+		Status:  "healthy",
+		Message: "Service is running",
+		Data: map[string]interface{}{
+			"uptime":    time.Since(h.StartTime).String(),
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
+	})
+}
+
+// Root handles "/", returning service info for unmatched sub-paths as 404.
+func (h *Handlers) Root(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, api.Response{
+		Status:  "success",
+		Message: "OpenTelemetry Demo Server",
+		Data: map[string]interface{}{
+			"endpoints": []string{"/good", "/bad", "/admin", "/health"},
+			"version":   "1.0.0",
+		},
+	})
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/digitalis-io/opentelemtry-local/pkg/api"
+)
+
+// Middleware wraps an http.Handler with additional behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chain composes middlewares into a single Middleware, applying them in the
+// order given (the first middleware in the list runs outermost).
+func Chain(mws ...Middleware) Middleware {
+	return func(final http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			final = mws[i](final)
+		}
+		return final
+	}
+}
+
+// LoggingMiddleware logs the method and path of every request.
+func LoggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RecoveryMiddleware turns a panic in a downstream handler into a 500 JSON
+// response instead of crashing the server.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("Recovered from panic: %v", rec)
+				api.WriteJSON(w, http.StatusInternalServerError, api.Response{
+					Status:  "error",
+					Message: "Internal server error occurred",
+					Data:    map[string]interface{}{"error_code": "PANIC_RECOVERED"},
+				})
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// OtelMiddleware wraps the next handler with OTel HTTP instrumentation so
+// that an incoming W3C traceparent header starts or continues a span named
+// after the route.
+func OtelMiddleware(route string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, route)
+	}
+}
+
+// jwksCacheTTL bounds how long a fetched JWKS key is trusted before the next
+// lookup for its kid triggers a refetch.
+const jwksCacheTTL = 5 * time.Minute
+
+// jwksHTTPClient bounds how long a JWKS fetch may take so a slow or
+// unresponsive JWKS endpoint can't hang an /admin request indefinitely.
+var jwksHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+type jwksCacheEntry struct {
+	key       interface{}
+	expiresAt time.Time
+}
+
+var (
+	jwksCacheMu    sync.Mutex
+	jwksCacheByKid = map[string]jwksCacheEntry{}
+)
+
+// resolveKey resolves the key used to verify a JWT, preferring a JWKS
+// endpoint (OIDC_JWKS_URL) over a static HS256 secret (ADMIN_HMAC_SECRET).
+func resolveKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if jwksURL := os.Getenv("OIDC_JWKS_URL"); jwksURL != "" {
+		return jwksKey(ctx, jwksURL, token)
+	}
+	secret := os.Getenv("ADMIN_HMAC_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("no ADMIN_HMAC_SECRET or OIDC_JWKS_URL configured")
+	}
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return []byte(secret), nil
+}
+
+// jwksKey returns the RSA public key matching token's "kid" header, serving
+// it from the cache when present and refreshing from jwksURL on a miss.
+func jwksKey(ctx context.Context, jwksURL string, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("token missing kid header")
+	}
+
+	if key, ok := cachedJWKSKey(kid); ok {
+		return key, nil
+	}
+
+	if err := refreshJWKSCache(ctx, jwksURL); err != nil {
+		return nil, err
+	}
+
+	if key, ok := cachedJWKSKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no JWKS key found for kid %q", kid)
+}
+
+func cachedJWKSKey(kid string) (interface{}, bool) {
+	jwksCacheMu.Lock()
+	defer jwksCacheMu.Unlock()
+
+	entry, ok := jwksCacheByKid[kid]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.key, true
+}
+
+// refreshJWKSCache fetches the JWKS document at jwksURL, using ctx (and a
+// bounded client timeout) so a slow endpoint can't hang the caller, and
+// populates the cache with every key it contains.
+func refreshJWKSCache(ctx context.Context, jwksURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return fmt.Errorf("building JWKS request: %w", err)
+	}
+
+	resp, err := jwksHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	jwksCacheMu.Lock()
+	defer jwksCacheMu.Unlock()
+
+	expiresAt := time.Now().Add(jwksCacheTTL)
+	for _, k := range jwks.Keys {
+		pubKey, err := parseRSAPublicKey(k.N, k.E)
+		if err != nil {
+			log.Printf("Skipping invalid JWKS entry for kid %q: %v", k.Kid, err)
+			continue
+		}
+		jwksCacheByKid[k.Kid] = jwksCacheEntry{key: pubKey, expiresAt: expiresAt}
+	}
+	return nil
+}
+
+// parseRSAPublicKey builds an *rsa.PublicKey from the base64url-encoded
+// modulus (n) and exponent (e) fields of a JWKS entry.
+func parseRSAPublicKey(nEncoded, eEncoded string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding exponent: %w", err)
+	}
+	if len(eBytes) > 8 {
+		return nil, fmt.Errorf("exponent too large: %d bytes", len(eBytes))
+	}
+
+	eBuf := make([]byte, 8)
+	copy(eBuf[8-len(eBytes):], eBytes)
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(binary.BigEndian.Uint64(eBuf)),
+	}, nil
+}
+
+// RequireRole returns a Middleware that authenticates requests via a Bearer
+// JWT and rejects any request whose claims don't carry the required role.
+func RequireRole(role string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			span := trace.SpanFromContext(ctx)
+
+			authHeader := r.Header.Get("Authorization")
+			const prefix = "Bearer "
+			if !strings.HasPrefix(authHeader, prefix) {
+				writeAuthError(w, span, http.StatusUnauthorized, "UNAUTHORIZED", "missing bearer token")
+				return
+			}
+			rawToken := strings.TrimPrefix(authHeader, prefix)
+
+			claims := &api.Claims{}
+			parsed, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+				return resolveKey(ctx, token)
+			})
+			if err != nil || !parsed.Valid {
+				writeAuthError(w, span, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired token")
+				return
+			}
+
+			if claims.Role != role {
+				writeAuthError(w, span, http.StatusForbidden, "FORBIDDEN", fmt.Sprintf("role %q required", role))
+				return
+			}
+
+			span.SetAttributes(semconv.EnduserID(claims.Subject))
+			next.ServeHTTP(w, r.WithContext(api.WithClaims(ctx, claims)))
+		})
+	}
+}
+
+// writeAuthError records an auth failure on the active span and writes a
+// structured JSON error response matching api.Response.
+func writeAuthError(w http.ResponseWriter, span trace.Span, status int, code, message string) {
+	err := fmt.Errorf(message)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, message)
+
+	api.WriteJSON(w, status, api.Response{
+		Status:  "error",
+		Message: message,
+		Data: map[string]interface{}{
+			"error_code": code,
+			"timestamp":  time.Now().Format(time.RFC3339),
+		},
+	})
+}
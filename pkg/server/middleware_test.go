@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/digitalis-io/opentelemtry-local/pkg/api"
+)
+
+func signToken(t *testing.T, secret, role string) string {
+	t.Helper()
+
+	claims := &api.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-123",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Role: role,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("signing test token: %v", err)
+	}
+	return signed
+}
+
+func TestRequireRole(t *testing.T) {
+	const secret = "test-secret"
+	t.Setenv("ADMIN_HMAC_SECRET", secret)
+
+	tests := []struct {
+		name       string
+		authHeader string
+		wantStatus int
+		wantNext   bool
+	}{
+		{"missing header", "", http.StatusUnauthorized, false},
+		{"malformed header", "Token abc", http.StatusUnauthorized, false},
+		{"invalid token", "Bearer not-a-jwt", http.StatusUnauthorized, false},
+		{"wrong role", "Bearer " + signToken(t, secret, "viewer"), http.StatusForbidden, false},
+		{"valid admin token", "Bearer " + signToken(t, secret, "admin"), http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nextCalled bool
+			var gotClaims *api.Claims
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				gotClaims, _ = api.ClaimsFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			RequireRole("admin")(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if nextCalled != tt.wantNext {
+				t.Errorf("next called = %v, want %v", nextCalled, tt.wantNext)
+			}
+			if tt.wantNext && (gotClaims == nil || gotClaims.Subject != "user-123") {
+				t.Errorf("expected claims to be attached to context, got %+v", gotClaims)
+			}
+		})
+	}
+}
+
+// encodeRSAComponents returns the base64url-encoded modulus and exponent of
+// pub, in the form a JWKS document represents them.
+func encodeRSAComponents(pub *rsa.PublicKey) (nEncoded, eEncoded string) {
+	eBuf := make([]byte, 8)
+	binary.BigEndian.PutUint64(eBuf, uint64(pub.E))
+	eBytes := eBuf
+	for len(eBytes) > 1 && eBytes[0] == 0 {
+		eBytes = eBytes[1:]
+	}
+	return base64.RawURLEncoding.EncodeToString(pub.N.Bytes()), base64.RawURLEncoding.EncodeToString(eBytes)
+}
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	nEncoded, eEncoded := encodeRSAComponents(&key.PublicKey)
+
+	tests := []struct {
+		name    string
+		n       string
+		e       string
+		wantErr bool
+	}{
+		{"valid key", nEncoded, eEncoded, false},
+		{"invalid modulus encoding", "not-base64url!!", eEncoded, true},
+		{"invalid exponent encoding", nEncoded, "not-base64url!!", true},
+		{"exponent too large", nEncoded, base64.RawURLEncoding.EncodeToString(make([]byte, 9)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRSAPublicKey(tt.n, tt.e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRSAPublicKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.E != key.PublicKey.E {
+				t.Errorf("parseRSAPublicKey().E = %d, want %d", got.E, key.PublicKey.E)
+			}
+		})
+	}
+}
+
+func TestJWKSKeyCachedAcrossLookups(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	const kid = "test-kid"
+	nEncoded, eEncoded := encodeRSAComponents(&key.PublicKey)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		jwks := map[string]interface{}{
+			"keys": []map[string]string{
+				{"kid": kid, "n": nEncoded, "e": eEncoded},
+			},
+		}
+		json.NewEncoder(w).Encode(jwks)
+	}))
+	defer srv.Close()
+
+	t.Cleanup(func() {
+		jwksCacheMu.Lock()
+		delete(jwksCacheByKid, kid)
+		jwksCacheMu.Unlock()
+	})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, &api.Claims{Role: "admin"})
+	token.Header["kid"] = kid
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		got, err := jwksKey(ctx, srv.URL, token)
+		if err != nil {
+			t.Fatalf("jwksKey() call %d: %v", i, err)
+		}
+		pub, ok := got.(*rsa.PublicKey)
+		if !ok || pub.N.Cmp(key.PublicKey.N) != 0 {
+			t.Fatalf("jwksKey() call %d returned unexpected key", i)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("JWKS endpoint hit %d times for 3 lookups, want 1 (cache not serving repeat lookups)", requests)
+	}
+}
@@ -0,0 +1,87 @@
+// Package server builds the demo's HTTP router and middleware chain and
+// runs it as a *http.Server with a graceful-shutdown lifecycle.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/digitalis-io/opentelemtry-local/pkg/handlers"
+)
+
+const (
+	readHeaderTimeout = 5 * time.Second
+	writeTimeout      = 15 * time.Second
+	idleTimeout       = 60 * time.Second
+)
+
+// Server wraps an *http.Server wired up with the demo's routes and
+// middleware chain.
+type Server struct {
+	httpServer *http.Server
+}
+
+// New builds a Server listening on addr that serves h's endpoints. Routing
+// goes through chi so that MetricsMiddleware can label metrics with the
+// registered route pattern instead of the raw request path.
+func New(addr string, h *handlers.Handlers) *Server {
+	r := chi.NewRouter()
+
+	route := func(pattern, name string, handler http.HandlerFunc, mws ...Middleware) {
+		chain := Chain(append([]Middleware{OtelMiddleware(name), LoggingMiddleware, RecoveryMiddleware, MetricsMiddleware}, mws...)...)
+		r.Method(http.MethodGet, pattern, chain(handler))
+	}
+
+	route("/good", "good", h.Good)
+	route("/bad", "bad", h.Bad)
+	route("/admin", "admin", h.Admin, RequireRole("admin"))
+	route("/health", "health", h.Health)
+	route("/", "root", h.Root)
+	r.Handle("/metrics", promhttp.Handler())
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           r,
+			ReadHeaderTimeout: readHeaderTimeout,
+			WriteTimeout:      writeTimeout,
+			IdleTimeout:       idleTimeout,
+		},
+	}
+}
+
+// Run starts the server and blocks until ctx is canceled, at which point it
+// shuts the server down, waiting up to drainTimeout for in-flight requests
+// (and their spans) to complete.
+func (s *Server) Run(ctx context.Context, drainTimeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on %s", s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down server (draining up to %v)", drainTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down server: %w", err)
+	}
+	return <-errCh
+}
@@ -0,0 +1,107 @@
+package simulate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes the latency, error, and chaos profile the default
+// Simulator applies to each named endpoint (a db statement or an external
+// API endpoint). Endpoints not listed fall back to Default.
+type Config struct {
+	Default   EndpointConfig            `json:"default" yaml:"default"`
+	Endpoints map[string]EndpointConfig `json:"endpoints" yaml:"endpoints"`
+}
+
+// EndpointConfig is the per-endpoint failure and latency profile.
+type EndpointConfig struct {
+	Latency          LatencyConfig `json:"latency" yaml:"latency"`
+	ErrorProbability float64       `json:"error_probability" yaml:"error_probability"`
+	ErrorType        ErrorType     `json:"error_type" yaml:"error_type"`
+	Chaos            *ChaosConfig  `json:"chaos,omitempty" yaml:"chaos,omitempty"`
+}
+
+// ErrorType selects what kind of error a simulated failure produces.
+type ErrorType string
+
+const (
+	ErrorTypeTimeout           ErrorType = "timeout"
+	ErrorType5xx               ErrorType = "5xx"
+	ErrorTypeConnectionRefused ErrorType = "connection_refused"
+)
+
+// LatencyDistribution selects how latency is sampled within [Min, Max].
+type LatencyDistribution string
+
+const (
+	DistributionUniform     LatencyDistribution = "uniform"
+	DistributionNormal      LatencyDistribution = "normal"
+	DistributionExponential LatencyDistribution = "exponential"
+)
+
+// LatencyConfig configures the latency distribution for an endpoint, all in
+// milliseconds.
+type LatencyConfig struct {
+	Distribution LatencyDistribution `json:"distribution" yaml:"distribution"`
+	MinMS        float64             `json:"min_ms" yaml:"min_ms"`
+	MaxMS        float64             `json:"max_ms" yaml:"max_ms"`
+	MeanMS       float64             `json:"mean_ms" yaml:"mean_ms"`
+}
+
+// ChaosConfig injects an extra latency spike on a periodic call count, e.g.
+// "every 10th call add 500ms".
+type ChaosConfig struct {
+	EveryNthCall int     `json:"every_nth_call" yaml:"every_nth_call"`
+	SpikeMS      float64 `json:"spike_ms" yaml:"spike_ms"`
+}
+
+// DefaultConfig returns the profile used when SIMULATOR_CONFIG is unset: a
+// uniform 10-100ms latency with no injected errors, matching the original
+// hardcoded demo behavior.
+func DefaultConfig() *Config {
+	return &Config{
+		Default: EndpointConfig{
+			Latency: LatencyConfig{
+				Distribution: DistributionUniform,
+				MinMS:        10,
+				MaxMS:        100,
+			},
+		},
+	}
+}
+
+// LoadConfig reads a Simulator Config from a YAML or JSON file, selecting
+// the decoder by file extension.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading simulator config: %w", err)
+	}
+
+	cfg := &Config{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing simulator config as JSON: %w", err)
+		}
+	default:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing simulator config as YAML: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// forEndpoint returns the EndpointConfig for name, falling back to Default
+// when name has no specific entry.
+func (c *Config) forEndpoint(name string) EndpointConfig {
+	if cfg, ok := c.Endpoints[name]; ok {
+		return cfg
+	}
+	return c.Default
+}
@@ -0,0 +1,98 @@
+package simulate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigForEndpoint(t *testing.T) {
+	cfg := &Config{
+		Default: EndpointConfig{ErrorProbability: 0.1},
+		Endpoints: map[string]EndpointConfig{
+			"/known": {ErrorProbability: 0.9},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     float64
+	}{
+		{"known endpoint uses its own config", "/known", 0.9},
+		{"unknown endpoint falls back to default", "/unknown", 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cfg.forEndpoint(tt.endpoint)
+			if got.ErrorProbability != tt.want {
+				t.Errorf("forEndpoint(%q).ErrorProbability = %v, want %v", tt.endpoint, got.ErrorProbability, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simulator.yaml")
+	contents := `
+default:
+  latency:
+    distribution: uniform
+    min_ms: 5
+    max_ms: 15
+  error_probability: 0.25
+  error_type: timeout
+endpoints:
+  /flaky:
+    latency:
+      distribution: normal
+      mean_ms: 50
+    error_probability: 0.5
+    error_type: 5xx
+    chaos:
+      every_nth_call: 10
+      spike_ms: 500
+`
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if got, want := cfg.Default.ErrorProbability, 0.25; got != want {
+		t.Errorf("Default.ErrorProbability = %v, want %v", got, want)
+	}
+	flaky, ok := cfg.Endpoints["/flaky"]
+	if !ok {
+		t.Fatalf("expected /flaky endpoint to be parsed")
+	}
+	if flaky.Chaos == nil || flaky.Chaos.EveryNthCall != 10 || flaky.Chaos.SpikeMS != 500 {
+		t.Errorf("unexpected chaos config: %+v", flaky.Chaos)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simulator.json")
+	contents := `{
+		"default": {
+			"latency": {"distribution": "exponential", "mean_ms": 20},
+			"error_probability": 0.05,
+			"error_type": "connection_refused"
+		}
+	}`
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Default.ErrorType != ErrorTypeConnectionRefused {
+		t.Errorf("Default.ErrorType = %v, want %v", cfg.Default.ErrorType, ErrorTypeConnectionRefused)
+	}
+}
@@ -0,0 +1,18 @@
+package simulate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	dbOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "db_operation_duration_seconds",
+		Help: "Latency of simulated database operations.",
+	}, []string{"operation"})
+
+	externalCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "external_call_duration_seconds",
+		Help: "Latency of simulated external API calls.",
+	}, []string{"endpoint", "outcome"})
+)
@@ -0,0 +1,203 @@
+// Package simulate provides fake database and external-API calls used to
+// give the demo server varied, realistic-looking trace shapes. The default
+// Simulator's latency, error, and chaos behavior per endpoint is driven by
+// a Config so the demo can be tuned without code changes.
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/digitalis-io/opentelemtry-local/pkg/simulate")
+
+// DBSimulator simulates a database call.
+type DBSimulator interface {
+	Query(ctx context.Context, stmt string) error
+}
+
+// ExternalAPISimulator simulates calling an external API.
+type ExternalAPISimulator interface {
+	Call(ctx context.Context, endpoint string) (interface{}, error)
+}
+
+// Simulator is the default DBSimulator and ExternalAPISimulator, driven by
+// a Config that specifies per-endpoint latency, error, and chaos behavior.
+type Simulator struct {
+	cfg *Config
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewSimulator creates a Simulator driven by cfg.
+func NewSimulator(cfg *Config) *Simulator {
+	return &Simulator{cfg: cfg, counts: make(map[string]int)}
+}
+
+// Query simulates a database call with latency and errors drawn from the
+// EndpointConfig registered for stmt (or Config.Default).
+func (s *Simulator) Query(ctx context.Context, stmt string) error {
+	ctx, span := tracer.Start(ctx, "db.query",
+		trace.WithAttributes(
+			semconv.DBSystemKey.String("demo-db"),
+			semconv.DBStatement(stmt),
+		),
+	)
+	defer span.End()
+
+	timer := prometheus.NewTimer(dbOperationDuration.WithLabelValues(stmt))
+	defer timer.ObserveDuration()
+
+	log.Printf("Database operation: %s", stmt)
+
+	err := s.run(ctx, stmt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Call simulates calling an external API, injecting the current span
+// context into the outgoing request headers so a real downstream service
+// would continue the trace.
+func (s *Simulator) Call(ctx context.Context, endpoint string) (interface{}, error) {
+	ctx, span := tracer.Start(ctx, "http.client",
+		trace.WithAttributes(
+			semconv.HTTPMethod(http.MethodGet),
+			semconv.HTTPURL(endpoint),
+		),
+	)
+	defer span.End()
+
+	if u, err := url.Parse(endpoint); err == nil {
+		span.SetAttributes(semconv.NetPeerName(u.Hostname()))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	log.Printf("Calling external API: %s", endpoint)
+
+	start := time.Now()
+	err = s.run(ctx, endpoint)
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	externalCallDuration.WithLabelValues(endpoint, outcome).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"external_data": fmt.Sprintf("Data from %s", endpoint),
+		"timestamp":     time.Now().Unix(),
+	}, nil
+}
+
+// run applies name's configured latency, chaos spike, and error injection,
+// blocking for the resulting duration (or returning early if ctx is done).
+func (s *Simulator) run(ctx context.Context, name string) error {
+	cfg := s.cfg.forEndpoint(name)
+
+	latency := sampleLatency(cfg.Latency)
+	if spike := s.chaosSpike(name, cfg.Chaos); spike > 0 {
+		latency += spike
+	}
+
+	select {
+	case <-time.After(latency):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if rand.Float64() < cfg.ErrorProbability {
+		return simulatedError(cfg.ErrorType)
+	}
+	return nil
+}
+
+// chaosSpike returns an extra latency spike if this is the Nth call to name
+// since the Simulator was created.
+func (s *Simulator) chaosSpike(name string, chaos *ChaosConfig) time.Duration {
+	if chaos == nil || chaos.EveryNthCall <= 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	s.counts[name]++
+	n := s.counts[name]
+	s.mu.Unlock()
+
+	if n%chaos.EveryNthCall == 0 {
+		return time.Duration(chaos.SpikeMS) * time.Millisecond
+	}
+	return 0
+}
+
+// sampleLatency draws a latency duration from lc's configured distribution.
+func sampleLatency(lc LatencyConfig) time.Duration {
+	minMS, maxMS := lc.MinMS, lc.MaxMS
+	if maxMS <= 0 {
+		maxMS = 100
+	}
+
+	var ms float64
+	switch lc.Distribution {
+	case DistributionNormal:
+		mean := lc.MeanMS
+		if mean == 0 {
+			mean = (minMS + maxMS) / 2
+		}
+		stddev := (maxMS - minMS) / 4
+		ms = rand.NormFloat64()*stddev + mean
+	case DistributionExponential:
+		mean := lc.MeanMS
+		if mean == 0 {
+			mean = (minMS + maxMS) / 2
+		}
+		ms = rand.ExpFloat64() * mean
+	default: // uniform
+		ms = minMS + rand.Float64()*(maxMS-minMS)
+	}
+
+	ms = math.Max(ms, 0)
+	return time.Duration(ms) * time.Millisecond
+}
+
+// simulatedError builds the error value for a configured ErrorType.
+func simulatedError(t ErrorType) error {
+	switch t {
+	case ErrorTypeTimeout:
+		return context.DeadlineExceeded
+	case ErrorTypeConnectionRefused:
+		return fmt.Errorf("connection refused")
+	case ErrorType5xx:
+		return fmt.Errorf("simulated upstream 5xx error")
+	default:
+		return fmt.Errorf("simulated error")
+	}
+}
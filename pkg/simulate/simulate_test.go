@@ -0,0 +1,61 @@
+package simulate
+
+import "testing"
+
+func TestSampleLatencyUniformStaysInBounds(t *testing.T) {
+	lc := LatencyConfig{Distribution: DistributionUniform, MinMS: 10, MaxMS: 20}
+
+	for i := 0; i < 100; i++ {
+		d := sampleLatency(lc)
+		if ms := d.Milliseconds(); ms < 10 || ms > 20 {
+			t.Fatalf("sampleLatency(uniform) = %v, want within [10ms, 20ms]", d)
+		}
+	}
+}
+
+func TestSampleLatencyNeverNegative(t *testing.T) {
+	distributions := []LatencyDistribution{DistributionUniform, DistributionNormal, DistributionExponential}
+
+	for _, dist := range distributions {
+		lc := LatencyConfig{Distribution: dist, MinMS: 10, MaxMS: 20, MeanMS: 15}
+		for i := 0; i < 100; i++ {
+			if d := sampleLatency(lc); d < 0 {
+				t.Fatalf("sampleLatency(%s) = %v, want >= 0", dist, d)
+			}
+		}
+	}
+}
+
+func TestChaosSpikeFiresOnEveryNthCall(t *testing.T) {
+	s := NewSimulator(DefaultConfig())
+	chaos := &ChaosConfig{EveryNthCall: 3, SpikeMS: 500}
+
+	var spikes int
+	for i := 1; i <= 9; i++ {
+		if s.chaosSpike("op", chaos) > 0 {
+			spikes++
+		}
+	}
+
+	if want := 3; spikes != want {
+		t.Errorf("got %d spikes in 9 calls with EveryNthCall=3, want %d", spikes, want)
+	}
+}
+
+func TestSimulatedErrorByType(t *testing.T) {
+	tests := []struct {
+		errType ErrorType
+		wantNil bool
+	}{
+		{ErrorTypeTimeout, false},
+		{ErrorType5xx, false},
+		{ErrorTypeConnectionRefused, false},
+		{ErrorType("unknown"), false},
+	}
+
+	for _, tt := range tests {
+		if err := simulatedError(tt.errType); (err == nil) != tt.wantNil {
+			t.Errorf("simulatedError(%q) = %v, wantNil=%v", tt.errType, err, tt.wantNil)
+		}
+	}
+}
@@ -2,265 +2,123 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"math/rand"
-	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
-)
-
-// User represents a user in our system
-type User struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-}
-
-// Response represents a standard API response
-type Response struct {
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-}
-
-// simulateDatabase simulates a database call with random latency
-func simulateDatabase(ctx context.Context, operation string) error {
-	// Random latency between 10-100ms to make traces interesting
-	latency := time.Duration(rand.Intn(90)+10) * time.Millisecond
-	log.Printf("Database operation: %s (latency: %v)", operation, latency)
-
-	select {
-	case <-time.After(latency):
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
-}
 
-// simulateExternalAPI simulates calling an external API
-func simulateExternalAPI(ctx context.Context, endpoint string) (interface{}, error) {
-	log.Printf("Calling external API: %s", endpoint)
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
-	// Simulate network latency
-	latency := time.Duration(rand.Intn(200)+50) * time.Millisecond
+	"github.com/digitalis-io/opentelemtry-local/pkg/handlers"
+	"github.com/digitalis-io/opentelemtry-local/pkg/server"
+	"github.com/digitalis-io/opentelemtry-local/pkg/simulate"
+)
 
-	select {
-	case <-time.After(latency):
-		// Simulate some data being returned
-		return map[string]interface{}{
-			"external_data": fmt.Sprintf("Data from %s", endpoint),
-			"timestamp":     time.Now().Unix(),
-		}, nil
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
-}
+const serviceName = "opentelemetry-local"
 
-// goodHandler handles the /good endpoint
-func goodHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	log.Printf("Processing good request from %s", r.RemoteAddr)
+// defaultDrainTimeout is used when DRAIN_TIMEOUT is unset or invalid.
+const defaultDrainTimeout = 10 * time.Second
 
-	// Simulate some business logic with database calls
-	if err := simulateDatabase(ctx, "SELECT users WHERE active=true"); err != nil {
-		log.Printf("Database error: %v", err)
-		http.Error(w, "Database unavailable", http.StatusServiceUnavailable)
-		return
+// drainTimeout bounds how long Run waits for in-flight requests (and their
+// spans) to complete once a shutdown signal arrives, configurable via
+// DRAIN_TIMEOUT (a Go duration string, e.g. "15s").
+func drainTimeout() time.Duration {
+	raw := os.Getenv("DRAIN_TIMEOUT")
+	if raw == "" {
+		return defaultDrainTimeout
 	}
-
-	// Simulate calling an external service
-	externalData, err := simulateExternalAPI(ctx, "https://api.example.com/status")
+	d, err := time.ParseDuration(raw)
 	if err != nil {
-		log.Printf("External API error: %v", err)
-		// Continue anyway for demo purposes
+		log.Printf("Invalid DRAIN_TIMEOUT %q, using default %v: %v", raw, defaultDrainTimeout, err)
+		return defaultDrainTimeout
 	}
-
-	// Create some users data
-	users := []User{
-		{ID: 1, Name: "Alice Johnson"},
-		{ID: 2, Name: "Bob Smith"},
-		{ID: 3, Name: "Charlie Brown"},
-	}
-
-	response := Response{
-		Status:  "success",
-		Message: "Request processed successfully",
-		Data: map[string]interface{}{
-			"users":         users,
-			"external_data": externalData,
-			"processed_at":  time.Now().Format(time.RFC3339),
-		},
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
-
-	log.Printf("Successfully processed good request")
+	return d
 }
 
-// badHandler handles the /bad endpoint
-func badHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	log.Printf("Processing bad request from %s", r.RemoteAddr)
-
-	// Simulate some processing that leads to an error
-	if err := simulateDatabase(ctx, "SELECT * FROM non_existent_table"); err != nil {
-		log.Printf("Expected database error: %v", err)
-	}
-
-	// Simulate multiple failed operations
-	operations := []string{
-		"validate_user_permissions",
-		"check_rate_limits",
-		"process_payment",
+// initTracerProvider configures a tracer provider that exports spans via
+// OTLP/HTTP to the endpoint given by OTEL_EXPORTER_OTLP_ENDPOINT (default
+// localhost:4318), and installs it along with a W3C trace context
+// propagator as the global defaults.
+func initTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4318"
 	}
 
-	for _, op := range operations {
-		log.Printf("Operation failed: %s", op)
-		// Add some artificial delay to make traces more interesting
-		time.Sleep(time.Duration(rand.Intn(20)+5) * time.Millisecond)
-	}
-
-	// Try external API call that will "fail"
-	_, err := simulateExternalAPI(ctx, "https://api.example.com/broken-endpoint")
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithInsecure(),
+	)
 	if err != nil {
-		log.Printf("External API call failed as expected: %v", err)
-	}
-
-	response := Response{
-		Status:  "error",
-		Message: "Internal server error occurred",
-		Data: map[string]interface{}{
-			"error_code": "INTERNAL_ERROR",
-			"timestamp":  time.Now().Format(time.RFC3339),
-		},
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding error response: %v", err)
-	}
-
-	log.Printf("Processed bad request with error response")
-}
-
-// adminHandler handles the /admin endpoint
-func adminHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	log.Printf("Admin access attempted from %s", r.RemoteAddr)
-
-	// Simulate authentication check
-	authToken := r.Header.Get("Authorization")
-	log.Printf("Checking authorization token: %s", authToken)
-
-	// Simulate database call to check permissions
-	if err := simulateDatabase(ctx, "SELECT permissions FROM users WHERE token=?"); err != nil {
-		log.Printf("Auth database error: %v", err)
-	}
-
-	// Simulate permission validation logic
-	operations := []string{
-		"validate_token_format",
-		"check_token_expiry",
-		"verify_admin_permissions",
-	}
-
-	for _, op := range operations {
-		log.Printf("Auth operation: %s", op)
-		time.Sleep(time.Duration(rand.Intn(15)+5) * time.Millisecond)
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
 	}
 
-	// Always return unauthorized for demo purposes
-	log.Printf("Authorization failed - insufficient permissions")
-
-	response := Response{
-		Status:  "error",
-		Message: "Unauthorized access - admin privileges required",
-		Data: map[string]interface{}{
-			"error_code":    "UNAUTHORIZED",
-			"required_role": "admin",
-			"timestamp":     time.Now().Format(time.RFC3339),
-		},
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating resource: %w", err)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding unauthorized response: %v", err)
-	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
 
-	log.Printf("Rejected admin request - unauthorized")
+	return tp, nil
 }
 
-// healthHandler provides a simple health check
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Health check from %s", r.RemoteAddr)
-
-	response := Response{
-		Status:  "healthy",
-		Message: "Service is running",
-		Data: map[string]interface{}{
-			"uptime":    time.Since(startTime).String(),
-			"timestamp": time.Now().Format(time.RFC3339),
-		},
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
-}
-
-var startTime time.Time
-
 func main() {
-	startTime = time.Now()
-
 	// Seed random number generator for consistent but varied latencies
 	rand.Seed(time.Now().UnixNano())
 
-	// Set up routes
-	http.HandleFunc("/good", goodHandler)
-	http.HandleFunc("/bad", badHandler)
-	http.HandleFunc("/admin", adminHandler)
-	http.HandleFunc("/health", healthHandler)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Root handler
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
+	tp, err := initTracerProvider(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracer provider: %v", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tp.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down tracer provider: %v", err)
 		}
+	}()
 
-		response := Response{
-			Status:  "success",
-			Message: "OpenTelemetry Demo Server",
-			Data: map[string]interface{}{
-				"endpoints": []string{"/good", "/bad", "/admin", "/health"},
-				"version":   "1.0.0",
-			},
+	simCfg := simulate.DefaultConfig()
+	if path := os.Getenv("SIMULATOR_CONFIG"); path != "" {
+		loaded, err := simulate.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to load simulator config: %v", err)
 		}
+		simCfg = loaded
+	}
+	sim := simulate.NewSimulator(simCfg)
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
-	})
+	h := handlers.New(time.Now(), sim, sim)
+	srv := server.New(":8080", h)
 
-	port := ":8080"
-	log.Printf("Starting server on port %s", port)
 	log.Printf("Available endpoints:")
 	log.Printf("  GET /        - Service info")
 	log.Printf("  GET /good    - Returns 200 with success response")
 	log.Printf("  GET /bad     - Returns 500 with error response")
-	log.Printf("  GET /admin   - Returns 401 unauthorized")
+	log.Printf("  GET /admin   - Requires a Bearer JWT with role=admin")
 	log.Printf("  GET /health  - Health check endpoint")
 
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	if err := srv.Run(ctx, drainTimeout()); err != nil {
+		log.Fatalf("Server failed: %v", err)
 	}
 }